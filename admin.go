@@ -0,0 +1,83 @@
+package boltstore
+
+import (
+	"time"
+
+	"code.google.com/p/gogoprotobuf/proto"
+
+	"github.com/boltdb/bolt"
+	"github.com/gorilla/sessions"
+)
+
+// SessionInfo describes a session stored in the bucket, for use by All.
+type SessionInfo struct {
+	ID string
+	// ExpiresAt is the zero Time if the session has no expiration.
+	ExpiresAt time.Time
+}
+
+// Delete immediately removes the session with the given ID from the
+// store, for server-side ("log out this session") invalidation. It is
+// safe to call with an ID that does not exist.
+func (s *BoltStore) Delete(sessionID string) error {
+	return s.delete(&sessions.Session{ID: sessionID})
+}
+
+// All returns information about every session currently in the store.
+func (s *BoltStore) All() ([]SessionInfo, error) {
+	var infos []SessionInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.config.DBOptions.BucketName).ForEach(func(k, v []byte) error {
+			sessionData := &Session{}
+			if err := proto.Unmarshal(v, sessionData); err != nil {
+				return err
+			}
+			info := SessionInfo{ID: string(k)}
+			if sessionData.ExpiresAt != nil && *sessionData.ExpiresAt > 0 {
+				info.ExpiresAt = time.Unix(*sessionData.ExpiresAt, 0)
+			}
+			infos = append(infos, info)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// DeleteByUser removes every session belonging to userID, for "log out
+// everywhere" and admin session-revocation flows. It requires
+// Config.UserIDKey to be set; with it unset there is no index to consult
+// and DeleteByUser is a no-op.
+func (s *BoltStore) DeleteByUser(userID string) error {
+	if s.config.UserIDKey == "" {
+		return nil
+	}
+	var deletedIDs []string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		userIndexBucket := tx.Bucket(s.config.DBOptions.UserIndexBucketName)
+		ub := userIndexBucket.Bucket([]byte(userID))
+		if ub == nil {
+			return nil
+		}
+		bucket := tx.Bucket(s.config.DBOptions.BucketName)
+		if err := ub.ForEach(func(k, _ []byte) error {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+			deletedIDs = append(deletedIDs, string(k))
+			return nil
+		}); err != nil {
+			return err
+		}
+		return userIndexBucket.DeleteBucket([]byte(userID))
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range deletedIDs {
+		s.cache.delete(id)
+	}
+	return nil
+}
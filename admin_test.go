@@ -0,0 +1,93 @@
+package boltstore
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/gorilla/sessions"
+)
+
+func newTestUserStore(t *testing.T) *BoltStore {
+	return newTestStoreConfig(t, Config{UserIDKey: "user_id"})
+}
+
+func saveUserSession(t *testing.T, s *BoltStore, id, userID string) {
+	t.Helper()
+	session := &sessions.Session{
+		ID:      id,
+		Values:  map[interface{}]interface{}{"user_id": userID},
+		Options: &sessions.Options{MaxAge: 300},
+	}
+	if err := s.save(session); err != nil {
+		t.Fatalf("save returned an error: %v", err)
+	}
+}
+
+func TestBoltStoreDelete(t *testing.T) {
+	s := newTestStore(t)
+	session := &sessions.Session{ID: "to-delete", Values: map[interface{}]interface{}{}, Options: &sessions.Options{MaxAge: 300}}
+	if err := s.save(session); err != nil {
+		t.Fatalf("save returned an error: %v", err)
+	}
+	if err := s.Delete("to-delete"); err != nil {
+		t.Fatalf("Delete returned an error: %v", err)
+	}
+	if ok, err := s.load(&sessions.Session{ID: "to-delete"}); err != nil || ok {
+		t.Errorf("load after Delete = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestBoltStoreAll(t *testing.T) {
+	s := newTestStore(t)
+	saveUserSession(t, s, "s1", "alice")
+	saveUserSession(t, s, "s2", "bob")
+
+	infos, err := s.All()
+	if err != nil {
+		t.Fatalf("All returned an error: %v", err)
+	}
+	var ids []string
+	for _, info := range infos {
+		ids = append(ids, info.ID)
+	}
+	sort.Strings(ids)
+	if want := []string{"s1", "s2"}; !equalStrings(ids, want) {
+		t.Errorf("All IDs = %v, want %v", ids, want)
+	}
+}
+
+func TestBoltStoreDeleteByUser(t *testing.T) {
+	s := newTestUserStore(t)
+	saveUserSession(t, s, "s1", "alice")
+	saveUserSession(t, s, "s2", "alice")
+	saveUserSession(t, s, "s3", "bob")
+
+	if err := s.DeleteByUser("alice"); err != nil {
+		t.Fatalf("DeleteByUser returned an error: %v", err)
+	}
+
+	infos, err := s.All()
+	if err != nil {
+		t.Fatalf("All returned an error: %v", err)
+	}
+	var ids []string
+	for _, info := range infos {
+		ids = append(ids, info.ID)
+	}
+	sort.Strings(ids)
+	if want := []string{"s3"}; !equalStrings(ids, want) {
+		t.Errorf("All IDs after DeleteByUser = %v, want %v", ids, want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
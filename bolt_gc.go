@@ -0,0 +1,97 @@
+package boltstore
+
+import (
+	"bytes"
+	"time"
+
+	"code.google.com/p/gogoprotobuf/proto"
+	"github.com/boltdb/bolt"
+)
+
+// gcBatchSize bounds how many keys are inspected per GC transaction, so a
+// single db.Update never holds Bolt's single writer lock for longer than it
+// takes to scan and delete one batch.
+const gcBatchSize = 100
+
+// GC starts a goroutine that periodically scans the bucket and deletes
+// sessions whose ExpiresAt has passed, so that abandoned session IDs do not
+// make the bucket grow without bound. It returns immediately; call StopGC
+// to terminate the goroutine, which Close does automatically.
+//
+// Modeled after Beego's session manager GC loop.
+func (s *BoltStore) GC(interval time.Duration) {
+	s.gcStop = make(chan struct{})
+	s.gcDone = make(chan struct{})
+	// Best effort: errors surface to no caller here, so simply retry on
+	// the next tick.
+	startGCLoop(interval, s.gcStop, s.gcDone, func() { s.gc() })
+}
+
+// StopGC terminates the goroutine started by GC, if one is running, and
+// waits for it to finish.
+func (s *BoltStore) StopGC() {
+	stopGCLoop(s.gcStop, s.gcDone)
+	s.gcStop, s.gcDone = nil, nil
+}
+
+// gc deletes all expired sessions from the bucket. The scan is split into
+// batches of gcBatchSize keys, each executed in its own read-write
+// transaction, so that GC never holds up other writers for longer than it
+// takes to inspect and delete one batch.
+func (s *BoltStore) gc() error {
+	var after []byte
+	for {
+		last, scanned, err := s.gcBatch(after)
+		if err != nil {
+			return err
+		}
+		if scanned < gcBatchSize {
+			return nil
+		}
+		after = last
+	}
+}
+
+// gcBatch scans up to gcBatchSize keys starting after the given key,
+// deleting any expired sessions among them, and returns the last key
+// scanned (so the next batch can resume from it) and how many keys were
+// scanned.
+func (s *BoltStore) gcBatch(after []byte) ([]byte, int, error) {
+	var (
+		scanned int
+		last    []byte
+		expired [][]byte
+	)
+	now := time.Now().Unix()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.config.DBOptions.BucketName)
+		c := bucket.Cursor()
+		var k, v []byte
+		if after == nil {
+			k, v = c.First()
+		} else {
+			k, v = c.Seek(after)
+			if k != nil && bytes.Equal(k, after) {
+				k, v = c.Next()
+			}
+		}
+		for ; k != nil && scanned < gcBatchSize; k, v = c.Next() {
+			scanned++
+			last = append([]byte(nil), k...)
+			sessionData := &Session{}
+			if err := proto.Unmarshal(v, sessionData); err != nil {
+				continue
+			}
+			if sessionData.ExpiresAt != nil && *sessionData.ExpiresAt > 0 && *sessionData.ExpiresAt < now {
+				expired = append(expired, append([]byte(nil), k...))
+			}
+		}
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return last, scanned, err
+}
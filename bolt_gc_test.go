@@ -0,0 +1,80 @@
+package boltstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.google.com/p/gogoprotobuf/proto"
+	"github.com/boltdb/bolt"
+)
+
+func newTestStore(tb testing.TB) *BoltStore {
+	return newTestStoreConfig(tb, Config{})
+}
+
+func newTestStoreConfig(tb testing.TB, config Config) *BoltStore {
+	tb.Helper()
+	config.DBOptions.Path = filepath.Join(tb.TempDir(), "bolt.db")
+	config.DBOptions.BucketName = []byte("sessions")
+	config.setDefault()
+	s := &BoltStore{config: config, keys: newKeyRing(config.KeyRotationGrace)}
+	if err := s.open(); err != nil {
+		tb.Fatalf("open returned an error: %v", err)
+	}
+	tb.Cleanup(func() { s.Close() })
+	return s
+}
+
+func putTestSession(t *testing.T, s *BoltStore, id string, expiresAt int64) {
+	t.Helper()
+	data, err := proto.Marshal(&Session{Values: []byte{}, ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.config.DBOptions.BucketName).Put([]byte(id), data)
+	})
+	if err != nil {
+		t.Fatalf("Put returned an error: %v", err)
+	}
+}
+
+func TestGCDeletesExpiredSessions(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().Unix()
+	putTestSession(t, s, "expired", now-60)
+	putTestSession(t, s, "fresh", now+60)
+	putTestSession(t, s, "no-expiry", 0)
+
+	if err := s.gc(); err != nil {
+		t.Fatalf("gc returned an error: %v", err)
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.config.DBOptions.BucketName)
+		if bucket.Get([]byte("expired")) != nil {
+			t.Error("expired session was not deleted")
+		}
+		if bucket.Get([]byte("fresh")) == nil {
+			t.Error("fresh session was deleted")
+		}
+		if bucket.Get([]byte("no-expiry")) == nil {
+			t.Error("session with no expiry was deleted")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View returned an error: %v", err)
+	}
+}
+
+func TestGCAndStopGC(t *testing.T) {
+	s := newTestStore(t)
+	s.GC(time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	s.StopGC()
+	if s.gcStop != nil || s.gcDone != nil {
+		t.Error("StopGC did not clear gcStop/gcDone")
+	}
+}
@@ -0,0 +1,96 @@
+package boltstore
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/securecookie"
+)
+
+// keyGeneration is one batch of codecs: the ones installed by New, or by a
+// single Rotate call.
+type keyGeneration struct {
+	number    int64
+	codecs    []securecookie.Codec
+	retiredAt time.Time // zero while this is still the current generation
+}
+
+// keyRing holds every key generation a BoltStore currently accepts, newest
+// first. Cookies are always encoded with the generation at index 0 (the most
+// recently installed); DecodeMulti tries every generation still in the ring, so a
+// session encoded under a just-retired generation keeps decoding until
+// Config.KeyRotationGrace elapses, after which it is dropped.
+type keyRing struct {
+	mu            sync.Mutex
+	grace         time.Duration
+	generations   []keyGeneration
+	nextGenNumber int64
+}
+
+// newKeyRing builds the initial ring (generation 0) from keyPairs.
+func newKeyRing(grace time.Duration, keyPairs ...[]byte) *keyRing {
+	return &keyRing{
+		grace: grace,
+		generations: []keyGeneration{{
+			number: 0,
+			codecs: securecookie.CodecsFromPairs(keyPairs...),
+		}},
+		nextGenNumber: 1,
+	}
+}
+
+// rotate retires the current generation and installs newKeyPairs as the
+// new current generation.
+func (k *keyRing) rotate(newKeyPairs ...[]byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	now := time.Now()
+	if len(k.generations) > 0 && k.generations[0].retiredAt.IsZero() {
+		k.generations[0].retiredAt = now
+	}
+	gen := keyGeneration{
+		number: k.nextGenNumber,
+		codecs: securecookie.CodecsFromPairs(newKeyPairs...),
+	}
+	k.nextGenNumber++
+	k.generations = append([]keyGeneration{gen}, k.generations...)
+	k.pruneLocked(now)
+}
+
+// pruneLocked drops generations that were retired more than grace ago. It
+// always keeps the current (index 0) generation regardless of grace.
+func (k *keyRing) pruneLocked(now time.Time) {
+	if k.grace <= 0 {
+		return
+	}
+	kept := k.generations[:0]
+	for _, g := range k.generations {
+		if !g.retiredAt.IsZero() && now.Sub(g.retiredAt) > k.grace {
+			continue
+		}
+		kept = append(kept, g)
+	}
+	k.generations = kept
+}
+
+// codecs returns every codec still accepted, in newest-to-oldest order, so
+// that securecookie.EncodeMulti signs with the current generation while
+// DecodeMulti still accepts any generation in the ring.
+func (k *keyRing) codecs() []securecookie.Codec {
+	k.mu.Lock()
+	k.pruneLocked(time.Now())
+	defer k.mu.Unlock()
+	var codecs []securecookie.Codec
+	for _, g := range k.generations {
+		codecs = append(codecs, g.codecs...)
+	}
+	return codecs
+}
+
+// current returns the generation number currently used to encode cookies
+// and new Session records.
+func (k *keyRing) current() int64 {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.generations[0].number
+}
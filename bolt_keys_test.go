@@ -0,0 +1,114 @@
+package boltstore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.google.com/p/gogoprotobuf/proto"
+	"github.com/boltdb/bolt"
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+func TestRotateKeepsInFlightSessionDecodable(t *testing.T) {
+	for _, maxAge := range []int{0, 300} {
+		maxAge := maxAge
+		t.Run(testNameForMaxAge(maxAge), func(t *testing.T) {
+			testRotateKeepsInFlightSessionDecodable(t, maxAge)
+		})
+	}
+}
+
+func testNameForMaxAge(maxAge int) string {
+	if maxAge == 0 {
+		return "MaxAge=0"
+	}
+	return "MaxAge=300"
+}
+
+func testRotateKeepsInFlightSessionDecodable(t *testing.T, maxAge int) {
+	oldKeyPair := securecookie.GenerateRandomKey(32)
+	s := newTestStoreConfig(t, Config{
+		KeyRotationGrace:      time.Hour,
+		ReencodeStaleSessions: true,
+		SessionOptions:        sessions.Options{MaxAge: maxAge},
+	})
+	s.keys = newKeyRing(time.Hour, oldKeyPair)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	session, err := s.New(r, "session")
+	if err != nil {
+		t.Fatalf("New returned an error: %v", err)
+	}
+	session.Values["k"] = "v"
+	if err := s.Save(r, w, session); err != nil {
+		t.Fatalf("Save returned an error: %v", err)
+	}
+	cookie := w.Result().Cookies()[0]
+	wantExpiresAt := readStoredExpiresAt(t, s, session.ID)
+
+	// Rotate to a new key pair; the cookie above was signed under the old one.
+	newKeyPair := securecookie.GenerateRandomKey(32)
+	s.Rotate(newKeyPair)
+
+	r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	r2.AddCookie(cookie)
+	reloaded, err := s.New(r2, "session")
+	if err != nil {
+		t.Fatalf("New after Rotate returned an error: %v", err)
+	}
+	if reloaded.IsNew {
+		t.Fatal("session encoded under the retired key pair was treated as new")
+	}
+	if reloaded.Values["k"] != "v" {
+		t.Errorf("reloaded.Values[k] = %v, want v", reloaded.Values["k"])
+	}
+
+	// New's opportunistic reencode must update KeyGeneration in place
+	// without touching the originally computed ExpiresAt.
+	if got := readStoredExpiresAt(t, s, session.ID); got != wantExpiresAt {
+		t.Errorf("ExpiresAt after reencode = %d, want %d (unchanged)", got, wantExpiresAt)
+	}
+
+	// Saving again re-signs the cookie and re-persists under the new generation.
+	w2 := httptest.NewRecorder()
+	if err := s.Save(r2, w2, reloaded); err != nil {
+		t.Fatalf("Save after Rotate returned an error: %v", err)
+	}
+	if got, want := w2.Result().Cookies()[0].Value, cookie.Value; got == want {
+		t.Error("cookie was not re-signed with the new key pair")
+	}
+}
+
+// readStoredExpiresAt reads back the raw ExpiresAt stored for id, bypassing
+// the cache, so tests can assert that a reencode left it untouched.
+func readStoredExpiresAt(t *testing.T, s *BoltStore, id string) int64 {
+	t.Helper()
+	var sessionData Session
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(s.config.DBOptions.BucketName).Get([]byte(id))
+		if raw == nil {
+			return nil
+		}
+		return proto.Unmarshal(raw, &sessionData)
+	})
+	if err != nil {
+		t.Fatalf("View returned an error: %v", err)
+	}
+	return sessionData.GetExpiresAt()
+}
+
+func TestRotateDropsOldKeysAfterGraceWindow(t *testing.T) {
+	oldKeyPair := securecookie.GenerateRandomKey(32)
+	ring := newKeyRing(time.Millisecond, oldKeyPair)
+	ring.rotate(securecookie.GenerateRandomKey(32))
+	time.Sleep(5 * time.Millisecond)
+	ring.rotate(securecookie.GenerateRandomKey(32)) // pruning runs as part of rotate
+
+	if len(ring.generations) != 2 {
+		t.Fatalf("len(generations) = %d, want 2 (current + the one still in grace)", len(ring.generations))
+	}
+}
@@ -0,0 +1,67 @@
+package boltstore
+
+import (
+	"code.google.com/p/gogoprotobuf/proto"
+
+	"github.com/boltdb/bolt"
+	"github.com/gorilla/sessions"
+)
+
+// addToUserIndex records that sessionID belongs to userID, under a nested
+// bucket keyed by userID inside the user index bucket.
+func addToUserIndex(tx *bolt.Tx, userIndexBucketName []byte, userID, sessionID string) error {
+	ub, err := tx.Bucket(userIndexBucketName).CreateBucketIfNotExists([]byte(userID))
+	if err != nil {
+		return err
+	}
+	return ub.Put([]byte(sessionID), []byte{})
+}
+
+// removeFromUserIndex removes sessionID from userID's nested bucket,
+// deleting the nested bucket entirely once it is empty.
+func removeFromUserIndex(tx *bolt.Tx, userIndexBucketName []byte, userID, sessionID string) error {
+	userIndexBucket := tx.Bucket(userIndexBucketName)
+	ub := userIndexBucket.Bucket([]byte(userID))
+	if ub == nil {
+		return nil
+	}
+	if err := ub.Delete([]byte(sessionID)); err != nil {
+		return err
+	}
+	// Bucket.Stats() walks on-disk pages and does not reliably reflect
+	// deletes made earlier in the same read-write transaction, so emptiness
+	// is checked with a cursor instead.
+	if k, _ := ub.Cursor().First(); k == nil {
+		return userIndexBucket.DeleteBucket([]byte(userID))
+	}
+	return nil
+}
+
+// userIDFromSession returns the user ID recorded in session.Values under
+// Config.UserIDKey, if any.
+func (s *BoltStore) userIDFromSession(session *sessions.Session) (string, bool) {
+	if s.config.UserIDKey == "" {
+		return "", false
+	}
+	v, ok := session.Values[s.config.UserIDKey]
+	if !ok {
+		return "", false
+	}
+	userID, ok := v.(string)
+	return userID, ok
+}
+
+// userIDFromData returns the user ID recorded in a marshalled Session's
+// Values, if any. It is used to find the old index entry to clean up when
+// a session is overwritten or deleted.
+func (s *BoltStore) userIDFromData(data []byte) (string, bool) {
+	sessionData := &Session{}
+	if err := proto.Unmarshal(data, sessionData); err != nil {
+		return "", false
+	}
+	tmp := &sessions.Session{}
+	if err := s.config.Serializer.Deserialize(sessionData.Values, tmp); err != nil {
+		return "", false
+	}
+	return s.userIDFromSession(tmp)
+}
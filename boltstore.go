@@ -0,0 +1,271 @@
+package boltstore
+
+import (
+	"code.google.com/p/gogoprotobuf/proto"
+	"net/http"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/gorilla/sessions"
+)
+
+// BoltStore is a Store backed by a Bolt bucket. It is the original and
+// still the default backend of this package; see MemoryStore and
+// RedisStore for alternatives.
+type BoltStore struct {
+	// keys holds every key pair generation still accepted; see Rotate.
+	keys   *keyRing
+	config Config
+	db     *bolt.DB
+	// gcStop and gcDone coordinate shutdown of the background GC
+	// goroutine started by GC; both are nil when GC is not running.
+	gcStop chan struct{}
+	gcDone chan struct{}
+	// cache is an optional in-process cache of encoded session data in
+	// front of the bucket; nil when Config.CacheSize is not positive.
+	cache *sessionCache
+}
+
+// Get returns a session for the given name after adding it to the registry.
+//
+// See gorilla/sessions FilesystemStore.Get().
+func (s *BoltStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return getSession(s, r, name)
+}
+
+// New returns a session for the given name without adding it to the registry.
+//
+// See gorilla/sessions FilesystemStore.New().
+func (s *BoltStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	return loadSession(s, s, s.keys.codecs(), &s.config.SessionOptions, r, name)
+}
+
+// Save adds a single session to the response.
+func (s *BoltStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	return saveSession(s, s.keys.codecs(), r, w, session)
+}
+
+// Rotate installs newKeyPairs as the key pairs used to sign/encrypt new
+// cookies and Session records, without invalidating sessions that are
+// still encoded under the previous key pairs: those keep decoding
+// successfully for Config.KeyRotationGrace before being dropped.
+func (s *BoltStore) Rotate(newKeyPairs ...[]byte) {
+	s.keys.rotate(newKeyPairs...)
+}
+
+// Close stops any running GC goroutine and closes the database.
+func (s *BoltStore) Close() error {
+	s.StopGC()
+	return s.db.Close()
+}
+
+// open Opens a database and sets it to the session store.
+func (s *BoltStore) open() error {
+	// Open a database.
+	db, err := bolt.Open(s.config.DBOptions.Path, 0666)
+	if err != nil {
+		return err
+	}
+	// Create the buckets if they do not exist.
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(s.config.DBOptions.BucketName); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(s.config.DBOptions.UserIndexBucketName)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.db = db
+	return nil
+}
+
+// load loads a session data from the database, preferring the in-process
+// cache over a Bolt transaction when Config.CacheSize is enabled. If
+// Config.ReencodeStaleSessions is set, a session found to have been saved
+// under a key generation Rotate has since superseded has its stored
+// KeyGeneration updated in place.
+// True is returned if there is a session data in the database.
+func (s *BoltStore) load(session *sessions.Session) (bool, error) {
+	if cached, ok := s.cache.get(session.ID); ok {
+		return s.decode(session, cached)
+	}
+
+	// exists represents whether a session data exists or not.
+	var exists bool
+	var data []byte
+	var stale *Session
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		id := []byte(session.ID)
+		bucket := tx.Bucket(s.config.DBOptions.BucketName)
+		// Get the session data.
+		raw := bucket.Get(id)
+		if raw == nil {
+			return nil
+		}
+		sessionData := &Session{}
+		// Convert the byte slice to the Session struct value.
+		if err := proto.Unmarshal(raw, sessionData); err != nil {
+			return err
+		}
+		// Check the expiration of the session data.
+		if *sessionData.ExpiresAt > 0 && *sessionData.ExpiresAt < time.Now().Unix() {
+			if err := bucket.Delete(id); err != nil {
+				return err
+			}
+			return nil
+		}
+		exists = true
+		data = append([]byte(nil), raw...)
+		if s.config.ReencodeStaleSessions && s.keyGenerationStale(sessionData) {
+			stale = sessionData
+		}
+		return s.config.Serializer.Deserialize(sessionData.Values, session)
+	})
+	if err == nil && exists {
+		s.cache.set(session.ID, data)
+		if stale != nil {
+			s.reencodeKeyGeneration(session, stale)
+		}
+	}
+	return exists, err
+}
+
+// keyGenerationStale reports whether sessionData was last saved under a key
+// generation that Rotate has since superseded.
+func (s *BoltStore) keyGenerationStale(sessionData *Session) bool {
+	return s.keys != nil && sessionData.KeyGeneration != nil && *sessionData.KeyGeneration != s.keys.current()
+}
+
+// reencodeKeyGeneration rewrites the stored record for session so its
+// KeyGeneration matches the ring's current generation, leaving Values and
+// ExpiresAt exactly as decoded in sessionData. It deliberately does not go
+// through save: save recomputes ExpiresAt from session.Options.MaxAge,
+// which at load time is the store-wide default (see loadSession), not
+// necessarily the MaxAge the session was originally saved with, and it
+// would reserialize Values for no reason. Failures are not fatal to the
+// load that triggered this, so they are ignored here and simply retried on
+// the next load or Save.
+func (s *BoltStore) reencodeKeyGeneration(session *sessions.Session, sessionData *Session) {
+	generation := s.keys.current()
+	sessionData.KeyGeneration = &generation
+	data, err := proto.Marshal(sessionData)
+	if err != nil {
+		return
+	}
+	id := []byte(session.ID)
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.config.DBOptions.BucketName).Put(id, data)
+	}); err != nil {
+		return
+	}
+	s.cache.set(session.ID, data)
+}
+
+// decode unmarshals cached Session bytes into session, re-checking
+// expiration since entries are not proactively evicted from the cache when
+// they expire. On expiry it evicts the entry from the cache and falls back
+// to a regular delete against Bolt. If Config.ReencodeStaleSessions is set,
+// it also updates the stored KeyGeneration of sessions found under a
+// superseded generation; see reencodeKeyGeneration.
+func (s *BoltStore) decode(session *sessions.Session, data []byte) (bool, error) {
+	sessionData := &Session{}
+	if err := proto.Unmarshal(data, sessionData); err != nil {
+		return false, err
+	}
+	if *sessionData.ExpiresAt > 0 && *sessionData.ExpiresAt < time.Now().Unix() {
+		s.cache.delete(session.ID)
+		return false, s.delete(session)
+	}
+	if err := s.config.Serializer.Deserialize(sessionData.Values, session); err != nil {
+		return false, err
+	}
+	if s.config.ReencodeStaleSessions && s.keyGenerationStale(sessionData) {
+		s.reencodeKeyGeneration(session, sessionData)
+	}
+	return true, nil
+}
+
+// delete removes the key-value from the database, removes it from the
+// user index if it belongs to a user, and evicts it from the cache.
+func (s *BoltStore) delete(session *sessions.Session) error {
+	id := []byte(session.ID)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.config.DBOptions.BucketName)
+		if s.config.UserIDKey != "" {
+			if raw := bucket.Get(id); raw != nil {
+				if userID, ok := s.userIDFromData(raw); ok {
+					if err := removeFromUserIndex(tx, s.config.DBOptions.UserIndexBucketName, userID, session.ID); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return bucket.Delete(id)
+	})
+	if err != nil {
+		return err
+	}
+	s.cache.delete(session.ID)
+	return nil
+}
+
+// save stores the session data in the database, updates the user index if
+// Config.UserIDKey is set, and writes through to the cache.
+func (s *BoltStore) save(session *sessions.Session) error {
+	values, err := s.config.Serializer.Serialize(session)
+	if err != nil {
+		return err
+	}
+	sessionData := NewSession(values, session.Options.MaxAge)
+	generation := s.keys.current()
+	sessionData.KeyGeneration = &generation
+	data, err := proto.Marshal(sessionData)
+	if err != nil {
+		return err
+	}
+	newUserID, hasNewUserID := s.userIDFromSession(session)
+	id := []byte(session.ID)
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.config.DBOptions.BucketName)
+		if s.config.UserIDKey != "" {
+			if old := bucket.Get(id); old != nil {
+				if oldUserID, ok := s.userIDFromData(old); ok && oldUserID != newUserID {
+					if err := removeFromUserIndex(tx, s.config.DBOptions.UserIndexBucketName, oldUserID, session.ID); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if err := bucket.Put(id, data); err != nil {
+			return err
+		}
+		if hasNewUserID {
+			return addToUserIndex(tx, s.config.DBOptions.UserIndexBucketName, newUserID, session.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.cache.set(session.ID, data)
+	return nil
+}
+
+// New creates and returns a session store.
+func New(config Config, keyPairs ...[]byte) (*BoltStore, error) {
+	config.setDefault()
+	store := &BoltStore{
+		keys:   newKeyRing(config.KeyRotationGrace, keyPairs...),
+		config: config,
+		cache:  newSessionCache(config.CacheSize, config.CacheTTL),
+	}
+	if err := store.open(); err != nil {
+		return nil, err
+	}
+	if config.GCInterval > 0 {
+		store.GC(config.GCInterval)
+	}
+	return store, nil
+}
@@ -0,0 +1,105 @@
+package boltstore
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// sessionCache is a size- and TTL-bounded in-process LRU cache of encoded
+// Session bytes (the same bytes that are stored in the Bolt bucket), keyed
+// by session ID. It sits in front of the bucket so that load/save do not
+// need a Bolt transaction on every call.
+//
+// A nil *sessionCache is valid and behaves as an always-miss, always-discard
+// cache; this is what newSessionCache returns when caching is disabled.
+type sessionCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	items map[string]*list.Element
+	order *list.List // front = most recently used
+}
+
+type cacheEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+}
+
+// newSessionCache returns a cache holding up to size entries, each valid
+// for ttl (zero means entries do not expire on their own). It returns nil
+// when size is not positive, which disables caching entirely.
+func newSessionCache(size int, ttl time.Duration) *sessionCache {
+	if size <= 0 {
+		return nil
+	}
+	return &sessionCache{
+		size:  size,
+		ttl:   ttl,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// get returns the cached bytes for key, if present and not expired.
+func (c *sessionCache) get(key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.data, true
+}
+
+// set stores data under key, evicting the least recently used entry if the
+// cache is over capacity.
+func (c *sessionCache) set(key string, data []byte) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = time.Now().Add(c.ttl)
+	}
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.data, entry.expiresAt = data, expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+	el := c.order.PushFront(&cacheEntry{key: key, data: data, expiresAt: expiresAt})
+	c.items[key] = el
+	if c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// delete removes key from the cache, if present.
+func (c *sessionCache) delete(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+func (c *sessionCache) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*cacheEntry).key)
+}
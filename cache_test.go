@@ -0,0 +1,107 @@
+package boltstore
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+)
+
+func TestSessionCacheDisabled(t *testing.T) {
+	c := newSessionCache(0, 0)
+	c.set("a", []byte("data"))
+	if _, ok := c.get("a"); ok {
+		t.Error("get found an entry in a disabled cache")
+	}
+}
+
+func TestSessionCacheGetSetDelete(t *testing.T) {
+	c := newSessionCache(2, 0)
+	c.set("a", []byte("1"))
+	if data, ok := c.get("a"); !ok || string(data) != "1" {
+		t.Fatalf("get(a) = %q, %v, want 1, true", data, ok)
+	}
+	c.delete("a")
+	if _, ok := c.get("a"); ok {
+		t.Error("get found a deleted entry")
+	}
+}
+
+func TestSessionCacheEvictsLRU(t *testing.T) {
+	c := newSessionCache(2, 0)
+	c.set("a", []byte("1"))
+	c.set("b", []byte("2"))
+	c.get("a") // touch a so b becomes the least recently used
+	c.set("c", []byte("3"))
+	if _, ok := c.get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Error("recently used entry was evicted")
+	}
+}
+
+func TestSessionCacheTTL(t *testing.T) {
+	c := newSessionCache(2, time.Millisecond)
+	c.set("a", []byte("1"))
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.get("a"); ok {
+		t.Error("get returned an entry past its TTL")
+	}
+}
+
+// BenchmarkStoreGet and BenchmarkStoreGetWithCache demonstrate the benefit
+// of the in-process cache: with it enabled, repeated Get calls for the same
+// session skip the db.Update/db.View round trip against Bolt entirely.
+
+func BenchmarkStoreGet(b *testing.B) {
+	benchmarkStoreGet(b, Config{})
+}
+
+func BenchmarkStoreGetWithCache(b *testing.B) {
+	benchmarkStoreGet(b, Config{CacheSize: 1000})
+}
+
+func benchmarkStoreGet(b *testing.B, config Config) {
+	s := newTestStoreConfig(b, config)
+	session := &sessions.Session{
+		ID:      "bench-session",
+		Values:  map[interface{}]interface{}{"k": "v"},
+		Options: &sessions.Options{MaxAge: 300},
+	}
+	if err := s.save(session); err != nil {
+		b.Fatalf("save returned an error: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		loaded := &sessions.Session{ID: session.ID}
+		if _, err := s.load(loaded); err != nil {
+			b.Fatalf("load returned an error: %v", err)
+		}
+	}
+}
+
+func BenchmarkStoreSave(b *testing.B) {
+	benchmarkStoreSave(b, Config{})
+}
+
+func BenchmarkStoreSaveWithCache(b *testing.B) {
+	benchmarkStoreSave(b, Config{CacheSize: 1000})
+}
+
+func benchmarkStoreSave(b *testing.B, config Config) {
+	s := newTestStoreConfig(b, config)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		session := &sessions.Session{
+			ID:      fmt.Sprintf("bench-session-%d", i%1000),
+			Values:  map[interface{}]interface{}{"k": "v"},
+			Options: &sessions.Options{MaxAge: 300},
+		}
+		if err := s.save(session); err != nil {
+			b.Fatalf("save returned an error: %v", err)
+		}
+	}
+}
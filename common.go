@@ -0,0 +1,103 @@
+package boltstore
+
+import (
+	"encoding/base32"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+// backendStore is implemented by each concrete backend's persistence
+// layer: it knows how to load, save and delete a single session's data.
+// getSession/loadSession/saveSession implement the cookie handling that is
+// common to every Store (BoltStore, MemoryStore, RedisStore) once, in
+// terms of backendStore.
+type backendStore interface {
+	load(session *sessions.Session) (bool, error)
+	save(session *sessions.Session) error
+	delete(session *sessions.Session) error
+}
+
+// newSessionID generates a new alphanumeric session ID, used by every
+// backend's saveSession when a session does not already have one.
+func newSessionID() string {
+	return strings.TrimRight(base32.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32)), "=")
+}
+
+// getSession returns a session for the given name after adding it to the
+// registry. See gorilla/sessions FilesystemStore.Get().
+func getSession(s Store, r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// loadSession returns a session for the given name without adding it to
+// the registry, decoding its ID from the named cookie and loading its data
+// from backend if the cookie is present and valid.
+func loadSession(s Store, backend backendStore, codecs []securecookie.Codec, options *sessions.Options, r *http.Request, name string) (*sessions.Session, error) {
+	var err error
+	session := sessions.NewSession(s, name)
+	session.Options = options
+	session.IsNew = true
+	if c, errCookie := r.Cookie(name); errCookie == nil {
+		err = securecookie.DecodeMulti(name, c.Value, &session.ID, codecs...)
+		if err == nil {
+			ok, err := backend.load(session)
+			session.IsNew = !(err == nil && ok) // not new if no error and data available
+		}
+	}
+	return session, err
+}
+
+// saveSession adds a single session to the response, storing it via
+// backend and setting the session cookie.
+func saveSession(backend backendStore, codecs []securecookie.Codec, r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		backend.delete(session)
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+	// Build an alphanumeric ID.
+	if session.ID == "" {
+		session.ID = newSessionID()
+	}
+	if err := backend.save(session); err != nil {
+		return err
+	}
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+// startGCLoop runs reap on every tick of interval until stop is closed,
+// then closes done. BoltStore.GC and MemoryStore.GC both build on this.
+func startGCLoop(interval time.Duration, stop, done chan struct{}, reap func()) {
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reap()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopGCLoop signals a loop started by startGCLoop to stop and waits for
+// it to exit. It is a no-op if stop is nil, i.e. GC was never started.
+func stopGCLoop(stop, done chan struct{}) {
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
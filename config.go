@@ -0,0 +1,73 @@
+package boltstore
+
+import (
+	"time"
+
+	"github.com/gorilla/sessions"
+)
+
+// Config represents configuration for a session store.
+type Config struct {
+	SessionOptions sessions.Options
+	DBOptions      DBOptions
+	// Serializer is used to encode/decode session.Values when storing them
+	// in the Bolt bucket. It defaults to GobSerializer.
+	Serializer SessionSerializer
+	// GCInterval, when greater than zero, makes New start a background
+	// goroutine that periodically removes expired sessions from the
+	// bucket. See BoltStore.GC.
+	GCInterval time.Duration
+	// CacheSize, when greater than zero, enables an in-process LRU cache
+	// of encoded session data keyed by session ID, holding up to this many
+	// entries. It is disabled (bypassed on every Get/Save) by default,
+	// which is the safe choice when the same database is opened by
+	// multiple processes.
+	CacheSize int
+	// CacheTTL bounds how long a cached entry is trusted before it is
+	// treated as a miss and re-read from Bolt. Zero means entries never
+	// expire from the cache on their own (they are still evicted by LRU).
+	CacheTTL time.Duration
+	// UserIDKey, when set, names the session.Values entry that holds a
+	// user identifier (a string). BoltStore.save and BoltStore.delete use
+	// it to maintain a secondary index from user ID to session IDs, which
+	// powers BoltStore.DeleteByUser. Leave empty to disable the index.
+	UserIDKey string
+	// KeyRotationGrace bounds how long BoltStore.Rotate keeps accepting
+	// cookies signed/encrypted under a key pair after it is superseded by
+	// a newer one. Zero means retired key pairs are kept forever (until
+	// the process restarts with a smaller keyPairs list).
+	KeyRotationGrace time.Duration
+	// ReencodeStaleSessions, when true, makes BoltStore.Get/New update the
+	// stored KeyGeneration of a session found under a key generation
+	// Rotate has since superseded, rather than waiting for the caller's
+	// next Save. It is off by default: doing this on every read serializes
+	// concurrent reads of old-generation sessions on Bolt's single writer,
+	// and it buys nothing on its own since the session cookie itself can
+	// only be re-signed by an explicit Save.
+	ReencodeStaleSessions bool
+}
+
+// DBOptions represents options for a database.
+type DBOptions struct {
+	BucketName []byte
+	Path       string
+	// UserIndexBucketName names the bucket that maps a user ID (as set via
+	// Config.UserIDKey) to the set of session IDs belonging to that user.
+	UserIndexBucketName []byte
+}
+
+// setDefault sets default values for the config if each field is empty.
+func (config *Config) setDefault() {
+	if config.DBOptions.Path == "" {
+		config.DBOptions.Path = "bolt.db"
+	}
+	if len(config.DBOptions.BucketName) == 0 {
+		config.DBOptions.BucketName = []byte("sessions")
+	}
+	if len(config.DBOptions.UserIndexBucketName) == 0 {
+		config.DBOptions.UserIndexBucketName = []byte("sessions_by_user")
+	}
+	if config.Serializer == nil {
+		config.Serializer = GobSerializer{}
+	}
+}
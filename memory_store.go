@@ -0,0 +1,129 @@
+package boltstore
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"code.google.com/p/gogoprotobuf/proto"
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+// MemoryStore is a Store that keeps encoded session data in an in-process
+// map instead of a Bolt bucket. It is useful for tests and for
+// single-process deployments that do not need sessions to survive a
+// restart.
+type MemoryStore struct {
+	codecs []securecookie.Codec
+	config Config
+	mu     sync.Mutex
+	data   map[string][]byte // session ID -> marshalled *Session
+	gcStop chan struct{}
+	gcDone chan struct{}
+}
+
+// NewMemoryStore creates and returns a MemoryStore.
+func NewMemoryStore(config Config, keyPairs ...[]byte) *MemoryStore {
+	config.setDefault()
+	s := &MemoryStore{
+		codecs: securecookie.CodecsFromPairs(keyPairs...),
+		config: config,
+		data:   make(map[string][]byte),
+	}
+	if config.GCInterval > 0 {
+		s.GC(config.GCInterval)
+	}
+	return s
+}
+
+// Get returns a session for the given name after adding it to the registry.
+func (s *MemoryStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return getSession(s, r, name)
+}
+
+// New returns a session for the given name without adding it to the registry.
+func (s *MemoryStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	return loadSession(s, s, s.codecs, &s.config.SessionOptions, r, name)
+}
+
+// Save adds a single session to the response.
+func (s *MemoryStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	return saveSession(s, s.codecs, r, w, session)
+}
+
+// Close stops the background GC goroutine, if any.
+func (s *MemoryStore) Close() error {
+	s.StopGC()
+	return nil
+}
+
+func (s *MemoryStore) load(session *sessions.Session) (bool, error) {
+	s.mu.Lock()
+	data, ok := s.data[session.ID]
+	s.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	sessionData := &Session{}
+	if err := proto.Unmarshal(data, sessionData); err != nil {
+		return false, err
+	}
+	if sessionData.ExpiresAt != nil && *sessionData.ExpiresAt > 0 && *sessionData.ExpiresAt < time.Now().Unix() {
+		return false, s.delete(session)
+	}
+	return true, s.config.Serializer.Deserialize(sessionData.Values, session)
+}
+
+func (s *MemoryStore) save(session *sessions.Session) error {
+	values, err := s.config.Serializer.Serialize(session)
+	if err != nil {
+		return err
+	}
+	data, err := proto.Marshal(NewSession(values, session.Options.MaxAge))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.data[session.ID] = data
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) delete(session *sessions.Session) error {
+	s.mu.Lock()
+	delete(s.data, session.ID)
+	s.mu.Unlock()
+	return nil
+}
+
+// GC starts a goroutine that periodically removes expired sessions from
+// the map. See BoltStore.GC.
+func (s *MemoryStore) GC(interval time.Duration) {
+	s.gcStop = make(chan struct{})
+	s.gcDone = make(chan struct{})
+	startGCLoop(interval, s.gcStop, s.gcDone, s.reap)
+}
+
+// StopGC terminates the goroutine started by GC, if one is running, and
+// waits for it to finish.
+func (s *MemoryStore) StopGC() {
+	stopGCLoop(s.gcStop, s.gcDone)
+	s.gcStop, s.gcDone = nil, nil
+}
+
+// reap deletes every expired entry from the map.
+func (s *MemoryStore) reap() {
+	now := time.Now().Unix()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, data := range s.data {
+		sessionData := &Session{}
+		if err := proto.Unmarshal(data, sessionData); err != nil {
+			continue
+		}
+		if sessionData.ExpiresAt != nil && *sessionData.ExpiresAt > 0 && *sessionData.ExpiresAt < now {
+			delete(s.data, id)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package boltstore
+
+import (
+	"testing"
+	"time"
+
+	"code.google.com/p/gogoprotobuf/proto"
+	"github.com/gorilla/sessions"
+)
+
+func TestMemoryStoreSaveLoadDelete(t *testing.T) {
+	s := NewMemoryStore(Config{})
+	defer s.Close()
+
+	session := &sessions.Session{
+		ID:      "mem-session",
+		Values:  map[interface{}]interface{}{"k": "v"},
+		Options: &sessions.Options{MaxAge: 300},
+	}
+	if err := s.save(session); err != nil {
+		t.Fatalf("save returned an error: %v", err)
+	}
+
+	loaded := &sessions.Session{ID: session.ID}
+	ok, err := s.load(loaded)
+	if err != nil {
+		t.Fatalf("load returned an error: %v", err)
+	}
+	if !ok {
+		t.Fatal("load reported no session, want one to exist")
+	}
+	if loaded.Values["k"] != "v" {
+		t.Errorf("loaded.Values[k] = %v, want v", loaded.Values["k"])
+	}
+
+	if err := s.delete(session); err != nil {
+		t.Fatalf("delete returned an error: %v", err)
+	}
+	if ok, err := s.load(&sessions.Session{ID: session.ID}); err != nil || ok {
+		t.Errorf("load after delete = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestMemoryStoreGCReapsExpired(t *testing.T) {
+	s := NewMemoryStore(Config{})
+	defer s.Close()
+
+	now := time.Now().Unix()
+	expiresAt := now - 60
+	s.data["expired"] = mustMarshalSession(t, []byte{}, expiresAt)
+	s.data["fresh"] = mustMarshalSession(t, []byte{}, now+60)
+
+	s.reap()
+
+	if _, ok := s.data["expired"]; ok {
+		t.Error("expired session was not reaped")
+	}
+	if _, ok := s.data["fresh"]; !ok {
+		t.Error("fresh session was reaped")
+	}
+}
+
+func mustMarshalSession(t *testing.T, values []byte, expiresAt int64) []byte {
+	t.Helper()
+	data, err := proto.Marshal(&Session{Values: values, ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+	return data
+}
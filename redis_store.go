@@ -0,0 +1,93 @@
+package boltstore
+
+import (
+	"net/http"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+// RedisStore is a Store backed by Redis. Unlike BoltStore and MemoryStore,
+// it relies on Redis key expiration (TTL) instead of a GC goroutine to
+// reap expired sessions, so it stores the serialized session.Values
+// directly rather than wrapping them in the Session protobuf envelope.
+type RedisStore struct {
+	codecs []securecookie.Codec
+	config Config
+	pool   *redis.Pool
+	// prefix is prepended to every session ID when forming the Redis key,
+	// so that a RedisStore can share a database with unrelated keys.
+	prefix string
+}
+
+// NewRedisStore creates and returns a RedisStore that stores sessions in
+// the given connection pool.
+func NewRedisStore(pool *redis.Pool, prefix string, config Config, keyPairs ...[]byte) *RedisStore {
+	config.setDefault()
+	return &RedisStore{
+		codecs: securecookie.CodecsFromPairs(keyPairs...),
+		config: config,
+		pool:   pool,
+		prefix: prefix,
+	}
+}
+
+// Get returns a session for the given name after adding it to the registry.
+func (s *RedisStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return getSession(s, r, name)
+}
+
+// New returns a session for the given name without adding it to the registry.
+func (s *RedisStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	return loadSession(s, s, s.codecs, &s.config.SessionOptions, r, name)
+}
+
+// Save adds a single session to the response.
+func (s *RedisStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	return saveSession(s, s.codecs, r, w, session)
+}
+
+// Close closes the underlying connection pool.
+func (s *RedisStore) Close() error {
+	return s.pool.Close()
+}
+
+func (s *RedisStore) key(id string) string {
+	return s.prefix + id
+}
+
+func (s *RedisStore) load(session *sessions.Session) (bool, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+	data, err := redis.Bytes(conn.Do("GET", s.key(session.ID)))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, s.config.Serializer.Deserialize(data, session)
+}
+
+func (s *RedisStore) save(session *sessions.Session) error {
+	data, err := s.config.Serializer.Serialize(session)
+	if err != nil {
+		return err
+	}
+	conn := s.pool.Get()
+	defer conn.Close()
+	if session.Options.MaxAge > 0 {
+		_, err = conn.Do("SETEX", s.key(session.ID), session.Options.MaxAge, data)
+	} else {
+		_, err = conn.Do("SET", s.key(session.ID), data)
+	}
+	return err
+}
+
+func (s *RedisStore) delete(session *sessions.Session) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("DEL", s.key(session.ID))
+	return err
+}
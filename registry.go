@@ -0,0 +1,48 @@
+package boltstore
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Provider constructs a Store from a Config and key pairs.
+type Provider func(config Config, keyPairs ...[]byte) (Store, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Provider{
+		"bolt": func(config Config, keyPairs ...[]byte) (Store, error) {
+			return New(config, keyPairs...)
+		},
+		"memory": func(config Config, keyPairs ...[]byte) (Store, error) {
+			return NewMemoryStore(config, keyPairs...), nil
+		},
+	}
+)
+
+// Register adds or replaces the provider for name, so that
+// NewFromConfig(name, ...) can construct it. RedisStore is not registered
+// by default since it requires a *redis.Pool that Config has no field
+// for; register it yourself once the pool exists, e.g.:
+//
+//	boltstore.Register("redis", func(c boltstore.Config, keyPairs ...[]byte) (boltstore.Store, error) {
+//		return boltstore.NewRedisStore(pool, "session:", c, keyPairs...), nil
+//	})
+func Register(name string, provider Provider) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = provider
+}
+
+// NewFromConfig constructs the Store registered under name, letting
+// callers switch backends via configuration without changing call sites.
+// It returns an error if name is not registered.
+func NewFromConfig(name string, config Config, keyPairs ...[]byte) (Store, error) {
+	registryMu.RLock()
+	provider, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("boltstore: no provider registered for %q", name)
+	}
+	return provider(config, keyPairs...)
+}
@@ -0,0 +1,31 @@
+package boltstore
+
+import "testing"
+
+func TestNewFromConfigMemory(t *testing.T) {
+	s, err := NewFromConfig("memory", Config{})
+	if err != nil {
+		t.Fatalf("NewFromConfig returned an error: %v", err)
+	}
+	defer s.Close()
+	if _, ok := s.(*MemoryStore); !ok {
+		t.Errorf("NewFromConfig(\"memory\", ...) = %T, want *MemoryStore", s)
+	}
+}
+
+func TestNewFromConfigUnknownProvider(t *testing.T) {
+	if _, err := NewFromConfig("nonexistent", Config{}); err == nil {
+		t.Fatal("NewFromConfig with an unregistered name returned a nil error")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-memory", func(config Config, keyPairs ...[]byte) (Store, error) {
+		return NewMemoryStore(config, keyPairs...), nil
+	})
+	s, err := NewFromConfig("test-memory", Config{})
+	if err != nil {
+		t.Fatalf("NewFromConfig returned an error: %v", err)
+	}
+	defer s.Close()
+}
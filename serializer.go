@@ -0,0 +1,78 @@
+package boltstore
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+
+	"github.com/gorilla/sessions"
+)
+
+// errJSONKeyNotString is returned by JSONSerializer.Serialize when a
+// session value uses a non-string map key, which encoding/json cannot encode.
+var errJSONKeyNotString = errors.New("boltstore: JSONSerializer requires session.Values keys to be strings")
+
+// SessionSerializer provides an interface for serializing/deserializing a
+// session's Values to and from the byte slice that is stored in the Bolt
+// bucket. It lets callers choose an encoding other than the default
+// encoding/gob, for example when session data must be inspected or shared
+// with non-Go services.
+type SessionSerializer interface {
+	Serialize(session *sessions.Session) ([]byte, error)
+	Deserialize(data []byte, session *sessions.Session) error
+}
+
+// GobSerializer serializes session.Values using encoding/gob. It is the
+// default serializer and supports arbitrary types registered with gob.
+type GobSerializer struct{}
+
+// Serialize encodes session.Values using encoding/gob.
+func (GobSerializer) Serialize(session *sessions.Session) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(session.Values); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Deserialize decodes data produced by Serialize back into session.Values.
+func (GobSerializer) Deserialize(data []byte, session *sessions.Session) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(&session.Values)
+}
+
+// JSONSerializer serializes session.Values using encoding/json. Unlike gob,
+// it produces output that can be read by non-Go services, but it requires
+// map keys to be strings since Go's json package does not support encoding
+// arbitrary interface{} map keys.
+type JSONSerializer struct{}
+
+// Serialize encodes session.Values using encoding/json.
+//
+// session.Values is a map[interface{}]interface{}, so it is copied into a
+// map with string keys before being marshalled; a key that is not a string
+// makes Serialize return errJSONKeyNotString.
+func (JSONSerializer) Serialize(session *sessions.Session) ([]byte, error) {
+	m := make(map[string]interface{}, len(session.Values))
+	for k, v := range session.Values {
+		ks, ok := k.(string)
+		if !ok {
+			return nil, errJSONKeyNotString
+		}
+		m[ks] = v
+	}
+	return json.Marshal(m)
+}
+
+// Deserialize decodes data produced by Serialize back into session.Values.
+func (JSONSerializer) Deserialize(data []byte, session *sessions.Session) error {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	session.Values = make(map[interface{}]interface{}, len(m))
+	for k, v := range m {
+		session.Values[k] = v
+	}
+	return nil
+}
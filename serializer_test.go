@@ -0,0 +1,61 @@
+package boltstore
+
+import (
+	"encoding/gob"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/sessions"
+)
+
+type customStruct struct {
+	Name string
+	Age  int
+}
+
+func init() {
+	gob.Register(customStruct{})
+	gob.Register(map[string]interface{}{})
+}
+
+func TestGobSerializerRoundTrip(t *testing.T) {
+	testSerializerRoundTrip(t, GobSerializer{}, map[interface{}]interface{}{
+		"string": "hello",
+		"int":    42,
+		"map":    map[string]interface{}{"a": "b"},
+		"struct": customStruct{Name: "gopher", Age: 10},
+	})
+}
+
+func TestJSONSerializerRoundTrip(t *testing.T) {
+	// JSONSerializer only supports string map keys, and numbers come back
+	// as float64, so use values that survive a JSON round trip unchanged.
+	testSerializerRoundTrip(t, JSONSerializer{}, map[interface{}]interface{}{
+		"string": "hello",
+		"float":  float64(42),
+		"map":    map[string]interface{}{"a": "b"},
+	})
+}
+
+func TestJSONSerializerNonStringKey(t *testing.T) {
+	session := &sessions.Session{Values: map[interface{}]interface{}{1: "one"}}
+	if _, err := (JSONSerializer{}).Serialize(session); err == nil {
+		t.Fatal("Serialize: expected an error for a non-string key, got nil")
+	}
+}
+
+func testSerializerRoundTrip(t *testing.T, serializer SessionSerializer, values map[interface{}]interface{}) {
+	t.Helper()
+	in := &sessions.Session{Values: values}
+	data, err := serializer.Serialize(in)
+	if err != nil {
+		t.Fatalf("Serialize returned an error: %v", err)
+	}
+	out := &sessions.Session{}
+	if err := serializer.Deserialize(data, out); err != nil {
+		t.Fatalf("Deserialize returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(in.Values, out.Values) {
+		t.Errorf("Deserialize = %#v, want %#v", out.Values, in.Values)
+	}
+}
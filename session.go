@@ -0,0 +1,18 @@
+package boltstore
+
+import "time"
+
+// NewSession returns a new Session whose Values field holds the
+// already-encoded session data and whose ExpiresAt is computed from maxAge.
+//
+// A maxAge of zero or less means the session never expires.
+func NewSession(values []byte, maxAge int) *Session {
+	var expiresAt int64
+	if maxAge > 0 {
+		expiresAt = time.Now().Unix() + int64(maxAge)
+	}
+	return &Session{
+		Values:    values,
+		ExpiresAt: &expiresAt,
+	}
+}
@@ -0,0 +1,50 @@
+// Code generated by protoc-gen-gogo.
+// source: session.proto
+// DO NOT EDIT!
+
+package boltstore
+
+import proto "code.google.com/p/gogoprotobuf/proto"
+import math "math"
+
+// Reference proto, json, and math imports to suppress error if they are not otherwise used.
+var _ = proto.Marshal
+var _ = math.Inf
+
+// Session represents the data stored for a single session entry in the
+// Bolt bucket: the caller-supplied values, an absolute expiration time,
+// and the key generation it was last saved under (see BoltStore.Rotate).
+type Session struct {
+	Values           []byte `protobuf:"bytes,1,opt,name=values" json:"values,omitempty"`
+	ExpiresAt        *int64 `protobuf:"varint,2,opt,name=expiresAt" json:"expiresAt,omitempty"`
+	KeyGeneration    *int64 `protobuf:"varint,3,opt,name=keyGeneration" json:"keyGeneration,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+
+func (m *Session) GetValues() []byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *Session) GetExpiresAt() int64 {
+	if m != nil && m.ExpiresAt != nil {
+		return *m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *Session) GetKeyGeneration() int64 {
+	if m != nil && m.KeyGeneration != nil {
+		return *m.KeyGeneration
+	}
+	return 0
+}
+
+func init() {
+}
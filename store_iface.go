@@ -0,0 +1,15 @@
+package boltstore
+
+import (
+	"github.com/gorilla/sessions"
+)
+
+// Store is implemented by every session store backend in this package
+// (BoltStore, MemoryStore, RedisStore). It is gorilla/sessions.Store plus
+// Close, so that callers can switch backends via Config without changing
+// call sites, and still shut a backend down cleanly (e.g. to stop a GC
+// goroutine or close a connection pool).
+type Store interface {
+	sessions.Store
+	Close() error
+}